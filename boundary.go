@@ -0,0 +1,138 @@
+package main
+
+// BoundaryMode controls how countNeighbours treats coordinates that fall
+// outside the grid.
+type BoundaryMode int
+
+const (
+	// BoundaryDead treats every out-of-bounds neighbour as permanently dead,
+	// the original behaviour of this simulator.
+	BoundaryDead BoundaryMode = iota
+	// BoundaryToroidal wraps indices around, so the grid behaves like the
+	// surface of a torus.
+	BoundaryToroidal
+	// BoundaryMirror reflects indices back into the grid, so the edge acts
+	// like a mirror.
+	BoundaryMirror
+	// BoundaryInfinite treats out-of-bounds as dead like BoundaryDead, but
+	// growGridIfNeeded grows the backing slice whenever a live cell comes
+	// close enough to the edge, so the pattern never actually runs out of room.
+	BoundaryInfinite
+)
+
+// boundaryModes lists the cycling order for the boundary-mode hotkey.
+var boundaryModes = []BoundaryMode{BoundaryDead, BoundaryToroidal, BoundaryMirror, BoundaryInfinite}
+
+func (b BoundaryMode) String() string {
+	switch b {
+	case BoundaryToroidal:
+		return "Toroidal"
+	case BoundaryMirror:
+		return "Mirror"
+	case BoundaryInfinite:
+		return "Infinite"
+	default:
+		return "Dead"
+	}
+}
+
+// reflectIndex maps i into [0, dim) by reflecting it off the nearest edge.
+// Neighbour offsets are always within 1 of a valid index, so a single
+// reflection is enough; it is not a general-purpose repeated-bounce mapping.
+func reflectIndex(i, dim int) int {
+	if i < 0 {
+		return -i - 1
+	}
+	if i >= dim {
+		return 2*dim - i - 1
+	}
+	return i
+}
+
+// infiniteEdgeMargin is how close (in cells) a live cell may come to the edge
+// of the grid before growGridIfNeeded grows it, under BoundaryInfinite.
+const infiniteEdgeMargin = 4
+
+// growGridIfNeeded expands grid's backing slice when BoundaryInfinite is
+// active and a live cell has come within infiniteEdgeMargin of an edge,
+// re-centering the existing pattern in the larger grid so it stays visible
+// and has room to keep growing.
+func growGridIfNeeded(grid *Grid) {
+	if grid.boundary != BoundaryInfinite {
+		return
+	}
+
+	if !liveNearEdge(grid) {
+		return
+	}
+
+	grown := grid.dimension + infiniteEdgeMargin*4
+	offset := (grown - grid.dimension) / 2
+	oldRows, oldDimension := grid.rows, grid.dimension
+
+	grid.dimension = grown
+	grid.wordsPerRow = wordsForDimension(grown)
+	grid.rows = make([][]uint64, grown)
+	for i := range grid.rows {
+		grid.rows[i] = make([]uint64, grid.wordsPerRow)
+	}
+	for i := 0; i < oldDimension; i++ {
+		for j := 0; j < oldDimension; j++ {
+			if oldRows[i][j/64]&(uint64(1)<<uint(j%64)) != 0 {
+				grid.setBit(i+offset, j+offset, true)
+			}
+		}
+	}
+}
+
+// liveNearEdge reports whether any live cell lies within infiniteEdgeMargin
+// of grid's border. growGridIfNeeded calls this every generation in
+// BoundaryInfinite mode (from countNeighbours), so it scans only the border
+// rows in full and just the margin columns of the interior rows, rather than
+// every one of grid.dimension^2 cells.
+func liveNearEdge(grid *Grid) bool {
+	dim, margin := grid.dimension, infiniteEdgeMargin
+	if margin*2 >= dim {
+		for i := 0; i < dim; i++ {
+			if anyBitSetInRange(grid.rows[i], 0, dim) {
+				return true
+			}
+		}
+		return false
+	}
+
+	for i := 0; i < margin; i++ {
+		if anyBitSetInRange(grid.rows[i], 0, dim) {
+			return true
+		}
+	}
+	for i := dim - margin; i < dim; i++ {
+		if anyBitSetInRange(grid.rows[i], 0, dim) {
+			return true
+		}
+	}
+	for i := margin; i < dim-margin; i++ {
+		if anyBitSetInRange(grid.rows[i], 0, margin) || anyBitSetInRange(grid.rows[i], dim-margin, dim) {
+			return true
+		}
+	}
+	return false
+}
+
+// anyBitSetInRange reports whether any bit in the column range [lo, hi) is
+// set in row, a bit-packed row where bit y%64 of word y/64 holds column y.
+func anyBitSetInRange(row []uint64, lo, hi int) bool {
+	for lo < hi {
+		word, bitInWord := lo/64, uint(lo%64)
+		take := hi - lo
+		if bitsLeft := 64 - int(bitInWord); take > bitsLeft {
+			take = bitsLeft
+		}
+		mask := (uint64(1)<<uint(take) - 1) << bitInWord
+		if row[word]&mask != 0 {
+			return true
+		}
+		lo += take
+	}
+	return false
+}
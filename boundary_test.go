@@ -0,0 +1,137 @@
+package main
+
+import "testing"
+
+// TestReflectIndex checks in-range indices pass through unchanged and
+// one-past-each-edge indices bounce back onto the last valid row/column,
+// the only offsets neighbour counting ever asks reflectIndex to handle.
+func TestReflectIndex(t *testing.T) {
+	const dim = 10
+	cases := []struct {
+		i, want int
+	}{
+		{-1, 0},
+		{0, 0},
+		{5, 5},
+		{dim - 1, dim - 1},
+		{dim, dim - 1},
+	}
+	for _, c := range cases {
+		if got := reflectIndex(c.i, dim); got != c.want {
+			t.Errorf("reflectIndex(%d, %d) = %d, want %d", c.i, dim, got, c.want)
+		}
+	}
+}
+
+// TestBoundaryModeString checks every named mode renders to a non-empty,
+// human-readable label, as shown in the on-screen HUD.
+func TestBoundaryModeString(t *testing.T) {
+	for _, b := range boundaryModes {
+		if s := b.String(); s == "" {
+			t.Errorf("BoundaryMode(%d).String() is empty", b)
+		}
+	}
+}
+
+// TestToroidalWrapIsConsistentAtEdges checks a live cell in one corner is
+// counted as a neighbour of the opposite corner under BoundaryToroidal,
+// the wrap-around behaviour that gives the mode its name.
+func TestToroidalWrapIsConsistentAtEdges(t *testing.T) {
+	const dim = 10
+	g := newTestGridForEditor(dim)
+	g.boundary = BoundaryToroidal
+	g.rule = conwayRule
+	g.setBit(0, 0, true)
+	countNeighbours(g)
+
+	if n := g.neighbourCountAt(dim-1, dim-1); n != 1 {
+		t.Errorf("corner (%d,%d) neighbour count = %d, want 1 (wrapped from (0,0))", dim-1, dim-1, n)
+	}
+}
+
+// scalarLiveNearEdge is the brute-force reference liveNearEdge replaced:
+// a full cell-by-cell scan for any live cell within infiniteEdgeMargin of
+// grid's border.
+func scalarLiveNearEdge(grid *Grid) bool {
+	dim, margin := grid.dimension, infiniteEdgeMargin
+	for i := 0; i < dim; i++ {
+		for j := 0; j < dim; j++ {
+			if !grid.getBit(i, j) {
+				continue
+			}
+			if i < margin || i >= dim-margin || j < margin || j >= dim-margin {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// TestLiveNearEdgeMatchesScalarReference cross-checks the border-only scan
+// against a full-grid scalar scan across cells placed at, just inside, and
+// just outside the margin, plus a grid smaller than 2*infiniteEdgeMargin
+// where every row falls within margin of some edge.
+func TestLiveNearEdgeMatchesScalarReference(t *testing.T) {
+	const dim = 20
+	cases := []struct {
+		name string
+		x, y int
+		live bool
+	}{
+		{"empty grid", 0, 0, false},
+		{"cell on top edge", 0, 10, true},
+		{"cell on left edge", 10, 0, true},
+		{"cell on right edge", dim - 1, 10, true},
+		{"cell on bottom edge", 10, dim - 1, true},
+		{"cell just inside margin", infiniteEdgeMargin - 1, 10, true},
+		{"cell just outside margin", infiniteEdgeMargin, 10, true},
+		{"cell dead center", dim / 2, dim / 2, true},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			g := newTestGridForEditor(dim)
+			if c.live {
+				g.setBit(c.x, c.y, true)
+			}
+			if want, got := scalarLiveNearEdge(g), liveNearEdge(g); want != got {
+				t.Fatalf("liveNearEdge() = %v, want %v", got, want)
+			}
+		})
+	}
+
+	t.Run("grid smaller than two margins", func(t *testing.T) {
+		const small = infiniteEdgeMargin*2 - 1
+		g := newTestGridForEditor(small)
+		g.setBit(small/2, small/2, true)
+		if want, got := scalarLiveNearEdge(g), liveNearEdge(g); want != got {
+			t.Fatalf("liveNearEdge() = %v, want %v", got, want)
+		}
+	})
+}
+
+// TestAnyBitSetInRange checks the word-boundary-crossing cases directly:
+// a range confined to one word, a range spanning a word boundary, and a
+// range spanning three words.
+func TestAnyBitSetInRange(t *testing.T) {
+	row := make([]uint64, 3)
+	row[1] = uint64(1) << 5 // bit 64+5 = 69
+
+	cases := []struct {
+		name   string
+		lo, hi int
+		want   bool
+	}{
+		{"within word 0, no hit", 0, 64, false},
+		{"within word 1, hits bit 69", 64, 128, true},
+		{"spans word 0 into word 1, hits bit 69", 60, 70, true},
+		{"spans all three words, hits bit 69", 0, 192, true},
+		{"spans word 1 into word 2, no hit", 70, 69 + 64, false},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := anyBitSetInRange(row, c.lo, c.hi); got != c.want {
+				t.Fatalf("anyBitSetInRange(row, %d, %d) = %v, want %v", c.lo, c.hi, got, c.want)
+			}
+		})
+	}
+}
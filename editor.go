@@ -0,0 +1,259 @@
+package main
+
+// Tool identifies what a mouse drag does to the grid.
+type Tool int
+
+const (
+	ToolPaint Tool = iota
+	ToolErase
+)
+
+// historyLimit caps how many undo snapshots Editor retains; the oldest is
+// discarded once the limit is reached.
+const historyLimit = 50
+
+const (
+	minBrushSize = 1
+	maxBrushSize = 10
+)
+
+// Editor owns every interactive mutation made to a Grid in the windowed
+// view: brush painting, box selection, clipboard copy/paste, and undo/redo.
+// Routing edits through it means countNeighbours runs once per committed
+// edit (once a drag, paste, or undo/redo completes) rather than once per
+// pixel painted.
+type Editor struct {
+	grid      *Grid
+	tool      Tool
+	brushSize int
+	dragging  bool
+
+	selecting    bool
+	hasSelection bool
+	selStart     [2]int
+	selEnd       [2]int
+
+	clipboard *Pattern
+
+	history []*Grid // undo stack of grid snapshots
+	future  []*Grid // redo stack, cleared by any new edit
+}
+
+// NewEditor returns an Editor with a single-cell brush, editing grid.
+func NewEditor(grid *Grid) *Editor {
+	return &Editor{grid: grid, brushSize: 1}
+}
+
+// cloneGrid deep-copies grid's bit-packed rows and scalar fields, for the
+// undo/redo stacks.
+func cloneGrid(g *Grid) *Grid {
+	clone := &Grid{
+		dimension:   g.dimension,
+		wordsPerRow: g.wordsPerRow,
+		rule:        g.rule,
+		boundary:    g.boundary,
+		rows:        make([][]uint64, len(g.rows)),
+	}
+	for i, row := range g.rows {
+		clone.rows[i] = append([]uint64(nil), row...)
+	}
+	return clone
+}
+
+// pushUndo snapshots the grid's current state onto the undo stack and
+// clears the redo stack, since any new edit invalidates it.
+func (e *Editor) pushUndo() {
+	e.history = append(e.history, cloneGrid(e.grid))
+	if len(e.history) > historyLimit {
+		e.history = e.history[len(e.history)-historyLimit:]
+	}
+	e.future = nil
+}
+
+// Undo restores the most recently snapshotted state, pushing the current
+// state onto the redo stack.
+func (e *Editor) Undo() {
+	if len(e.history) == 0 {
+		return
+	}
+	prev := e.history[len(e.history)-1]
+	e.history = e.history[:len(e.history)-1]
+	e.future = append(e.future, cloneGrid(e.grid))
+	e.restore(prev)
+}
+
+// Redo re-applies a state previously undone.
+func (e *Editor) Redo() {
+	if len(e.future) == 0 {
+		return
+	}
+	next := e.future[len(e.future)-1]
+	e.future = e.future[:len(e.future)-1]
+	e.history = append(e.history, cloneGrid(e.grid))
+	e.restore(next)
+}
+
+func (e *Editor) restore(snapshot *Grid) {
+	e.grid.dimension = snapshot.dimension
+	e.grid.wordsPerRow = snapshot.wordsPerRow
+	e.grid.rows = snapshot.rows
+	e.grid.rule = snapshot.rule
+	e.grid.boundary = snapshot.boundary
+	countNeighbours(e.grid)
+}
+
+// StartDrag begins a paint or erase stroke, snapshotting undo state once for
+// the whole stroke rather than once per painted cell.
+func (e *Editor) StartDrag(tool Tool) {
+	e.pushUndo()
+	e.dragging = true
+	e.tool = tool
+}
+
+// PaintAt paints or erases every cell within the current brush radius of
+// (x, y), according to the active drag tool. Call once per frame while
+// dragging; it does not recount neighbours, so many calls stay cheap.
+func (e *Editor) PaintAt(x, y int) {
+	if !e.dragging {
+		return
+	}
+	alive := e.tool == ToolPaint
+	r := e.brushSize - 1
+	for dx := -r; dx <= r; dx++ {
+		for dy := -r; dy <= r; dy++ {
+			px, py := x+dx, y+dy
+			if px < 0 || px >= e.grid.dimension || py < 0 || py >= e.grid.dimension {
+				continue
+			}
+			e.grid.setBit(px, py, alive)
+		}
+	}
+}
+
+// EndDrag commits the stroke, running countNeighbours exactly once no
+// matter how many cells were painted.
+func (e *Editor) EndDrag() {
+	if !e.dragging {
+		return
+	}
+	e.dragging = false
+	countNeighbours(e.grid)
+}
+
+// StartSelection begins a selection-rectangle drag at (x, y).
+func (e *Editor) StartSelection(x, y int) {
+	e.selecting = true
+	e.hasSelection = true
+	e.selStart = [2]int{x, y}
+	e.selEnd = [2]int{x, y}
+}
+
+// UpdateSelection extends the in-progress selection rectangle to (x, y).
+func (e *Editor) UpdateSelection(x, y int) {
+	if e.selecting {
+		e.selEnd = [2]int{x, y}
+	}
+}
+
+// EndSelection finishes a selection drag; the rectangle remains active
+// until ClearSelection is called or a new paint/erase drag starts.
+func (e *Editor) EndSelection() {
+	e.selecting = false
+}
+
+// ClearSelection drops the current selection rectangle.
+func (e *Editor) ClearSelection() {
+	e.selecting = false
+	e.hasSelection = false
+}
+
+// selectionBounds returns the selection rectangle normalised to
+// (x0, y0) <= (x1, y1), with x1/y1 exclusive, and clamped to the grid's
+// bounds: selStart/selEnd are raw mouse-derived coordinates and can fall
+// outside [0, dimension) while a drag is in progress near the window edge.
+func (e *Editor) selectionBounds() (x0, y0, x1, y1 int) {
+	x0, x1 = e.selStart[0], e.selEnd[0]
+	if x0 > x1 {
+		x0, x1 = x1, x0
+	}
+	y0, y1 = e.selStart[1], e.selEnd[1]
+	if y0 > y1 {
+		y0, y1 = y1, y0
+	}
+	x1++
+	y1++
+
+	x0 = clampInt(x0, 0, e.grid.dimension)
+	x1 = clampInt(x1, 0, e.grid.dimension)
+	y0 = clampInt(y0, 0, e.grid.dimension)
+	y1 = clampInt(y1, 0, e.grid.dimension)
+	return x0, y0, x1, y1
+}
+
+// clampInt restricts v to [lo, hi].
+func clampInt(v, lo, hi int) int {
+	if v < lo {
+		return lo
+	}
+	if v > hi {
+		return hi
+	}
+	return v
+}
+
+// Copy extracts the live cells within the current selection into the
+// clipboard, relative to the selection's top-left corner.
+func (e *Editor) Copy() {
+	if !e.hasSelection {
+		return
+	}
+	x0, y0, x1, y1 := e.selectionBounds()
+	p := &Pattern{Width: x1 - x0, Height: y1 - y0}
+	for x := x0; x < x1; x++ {
+		for y := y0; y < y1; y++ {
+			if e.grid.getBit(x, y) {
+				p.Cells = append(p.Cells, [2]int{x - x0, y - y0})
+			}
+		}
+	}
+	e.clipboard = p
+}
+
+// Paste stamps the clipboard pattern onto the grid with its origin at
+// (x, y) and recounts neighbours once.
+func (e *Editor) Paste(x, y int) {
+	if e.clipboard == nil {
+		return
+	}
+	e.pushUndo()
+	StampPattern(e.grid, e.clipboard, x, y)
+	countNeighbours(e.grid)
+}
+
+// RotateClipboard rotates the clipboard pattern 90 degrees clockwise, so the
+// next paste places the rotated form.
+func (e *Editor) RotateClipboard() {
+	if e.clipboard != nil {
+		e.clipboard = RotatePattern90(e.clipboard)
+	}
+}
+
+// FlipClipboard mirrors the clipboard pattern left-to-right, so the next
+// paste places the flipped form.
+func (e *Editor) FlipClipboard() {
+	if e.clipboard != nil {
+		e.clipboard = FlipPatternHorizontal(e.clipboard)
+	}
+}
+
+// AdjustBrush grows or shrinks the brush radius by delta, clamped to
+// [minBrushSize, maxBrushSize].
+func (e *Editor) AdjustBrush(delta int) {
+	e.brushSize += delta
+	if e.brushSize < minBrushSize {
+		e.brushSize = minBrushSize
+	}
+	if e.brushSize > maxBrushSize {
+		e.brushSize = maxBrushSize
+	}
+}
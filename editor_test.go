@@ -0,0 +1,53 @@
+package main
+
+import "testing"
+
+// newTestGridForEditor builds a grid of exactly dimension cells; initGrid
+// always resets dimension to the package-wide gridDimension constant, so
+// tests that need a small, fast grid construct one directly instead.
+func newTestGridForEditor(dimension int) *Grid {
+	grid := &Grid{dimension: dimension, wordsPerRow: wordsForDimension(dimension)}
+	grid.rows = make([][]uint64, dimension)
+	for i := range grid.rows {
+		grid.rows[i] = make([]uint64, grid.wordsPerRow)
+	}
+	return countNeighbours(grid)
+}
+
+// TestSelectionBoundsClampsOutOfRangeDrag guards against a real panic: a
+// shift-drag that carries the mouse outside the window (mouse capture keeps
+// delivering negative/over-range coordinates while the button is held)
+// previously made Copy index grid.rows with a negative index.
+func TestSelectionBoundsClampsOutOfRangeDrag(t *testing.T) {
+	cases := []struct {
+		name           string
+		start, end     [2]int
+		wantX0, wantY0 int
+		wantX1, wantY1 int
+	}{
+		{"fully in range", [2]int{2, 3}, [2]int{5, 6}, 2, 3, 6, 7},
+		{"end drifts negative", [2]int{5, 5}, [2]int{-3, -2}, 0, 0, 6, 6},
+		{"end drifts past the edge", [2]int{5, 5}, [2]int{1000, 1000}, 5, 5, 10, 10},
+		{"start negative, end in range", [2]int{-5, -5}, [2]int{2, 2}, 0, 0, 3, 3},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			e := NewEditor(newTestGridForEditor(10))
+			e.StartSelection(c.start[0], c.start[1])
+			e.UpdateSelection(c.end[0], c.end[1])
+			x0, y0, x1, y1 := e.selectionBounds()
+			if x0 != c.wantX0 || y0 != c.wantY0 || x1 != c.wantX1 || y1 != c.wantY1 {
+				t.Fatalf("selectionBounds() = (%d,%d,%d,%d), want (%d,%d,%d,%d)", x0, y0, x1, y1, c.wantX0, c.wantY0, c.wantX1, c.wantY1)
+			}
+		})
+	}
+}
+
+// TestCopyOutOfRangeSelectionDoesNotPanic is the exact repro from review: a
+// selection dragged out of bounds must not panic Copy.
+func TestCopyOutOfRangeSelectionDoesNotPanic(t *testing.T) {
+	e := NewEditor(newTestGridForEditor(10))
+	e.StartSelection(5, 5)
+	e.UpdateSelection(-3, -2)
+	e.Copy()
+}
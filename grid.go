@@ -0,0 +1,386 @@
+package main
+
+import (
+	"math/rand/v2"
+	"runtime"
+	"sync"
+	"time"
+)
+
+// Cell is a view-only snapshot of one grid position, used by the draw path
+// and debug output; Grid itself stores state bit-packed, not as a live Cell
+// per position.
+type Cell struct {
+	x          int
+	y          int
+	state      bool // true = alive, false = dead
+	neighbours int
+}
+
+// Grid is a bit-packed Game of Life universe: rows[x] packs the whole row's
+// state into wordsPerRow uint64 words (64 cells per word, bit y%64 of word
+// y/64), so a generation step operates 64 cells at a time via SWAR bit
+// tricks instead of one cell at a time.
+type Grid struct {
+	rows            [][]uint64      // live/dead state, bit-packed
+	counts          [][4]([]uint64) // per-row neighbour count, as 4 bit-planes (bit0..bit3), populated by countNeighbours
+	dimension       int
+	wordsPerRow     int
+	lifetime        int
+	paused          bool
+	tickRate        float32
+	rule            Rule
+	boundary        BoundaryMode
+	initGrid        func(*Grid) *Grid
+	countNeighbours func(*Grid) *Grid
+	updateGrid      func(*Grid) *Grid
+	drawGrid        func(*Grid)
+
+	// rng, if set, seeds initGrid's random noise deterministically (set by
+	// -seed); nil means seed from the current time instead.
+	rng *rand.Rand
+	// seedPattern, if set, is stamped onto the grid by initGrid instead of
+	// seeding it with random noise (set by -pattern).
+	seedPattern *Pattern
+}
+
+func wordsForDimension(dimension int) int {
+	return (dimension + 63) / 64
+}
+
+// getBit reports whether cell (x, y) is alive.
+func (g *Grid) getBit(x, y int) bool {
+	return g.rows[x][y/64]&(uint64(1)<<uint(y%64)) != 0
+}
+
+// setBit sets cell (x, y) alive or dead.
+func (g *Grid) setBit(x, y int, alive bool) {
+	mask := uint64(1) << uint(y%64)
+	if alive {
+		g.rows[x][y/64] |= mask
+	} else {
+		g.rows[x][y/64] &^= mask
+	}
+}
+
+// cellAt decodes the view-only Cell for (x, y) on demand, for the draw path.
+func (g *Grid) cellAt(x, y int) Cell {
+	return Cell{x: x, y: y, state: g.getBit(x, y), neighbours: g.neighbourCountAt(x, y)}
+}
+
+// neighbourCountAt decodes the neighbour count last computed by
+// countNeighbours for (x, y) from its 4 bit-planes.
+func (g *Grid) neighbourCountAt(x, y int) int {
+	if g.counts == nil {
+		return 0
+	}
+	word, bit := y/64, uint(y%64)
+	planes := g.counts[x]
+	n := 0
+	for b := 0; b < 4; b++ {
+		if planes[b][word]&(uint64(1)<<bit) != 0 {
+			n |= 1 << b
+		}
+	}
+	return n
+}
+
+func initGrid(grid *Grid) *Grid {
+	grid.dimension = gridDimension
+	grid.lifetime = gridLifeTime
+	grid.tickRate = tickRate
+	grid.paused = startPaused
+	if grid.rule == (Rule{}) {
+		grid.rule = conwayRule
+	}
+	grid.wordsPerRow = wordsForDimension(grid.dimension)
+	grid.rows = make([][]uint64, grid.dimension)
+	for x := range grid.rows {
+		grid.rows[x] = make([]uint64, grid.wordsPerRow)
+	}
+
+	if grid.seedPattern != nil {
+		x := (grid.dimension - grid.seedPattern.Width) / 2
+		y := (grid.dimension - grid.seedPattern.Height) / 2
+		StampPattern(grid, grid.seedPattern, x, y)
+		return grid
+	}
+
+	rng := grid.rng
+	if rng == nil {
+		rng = rand.New(rand.NewPCG(uint64(time.Now().UnixNano()), uint64(time.Now().UnixNano())))
+	}
+	for x := range grid.rows {
+		for y := 0; y < grid.dimension; y++ {
+			if useRandom && rng.Float32() < probability {
+				grid.setBit(x, y, true)
+			}
+		}
+	}
+	return grid
+}
+
+// parallelRows splits [0, dimension) into roughly runtime.NumCPU() stripes
+// and runs work on each stripe concurrently, waiting for all of them to
+// finish. Each stripe must be safe to process independently of the others.
+func parallelRows(dimension int, work func(xStart, xEnd int)) {
+	workers := runtime.NumCPU()
+	if workers > dimension {
+		workers = dimension
+	}
+	if workers < 1 {
+		workers = 1
+	}
+	rowsPerWorker := (dimension + workers - 1) / workers
+
+	var wg sync.WaitGroup
+	for start := 0; start < dimension; start += rowsPerWorker {
+		end := min(start+rowsPerWorker, dimension)
+		wg.Add(1)
+		go func(start, end int) {
+			defer wg.Done()
+			work(start, end)
+		}(start, end)
+	}
+	wg.Wait()
+}
+
+// countNeighbours computes, for every row, the neighbour count of each cell
+// as 4 bit-planes (grid.counts), using bit-parallel SWAR adders across whole
+// rows at a time for Dead/Toroidal/Infinite boundaries, and a scalar
+// per-cell fallback for Mirror (whose reflected indexing isn't a clean fit
+// for the word-at-a-time shifts the other modes use). Rows are independent
+// of each other, so the work is split across a worker pool.
+func countNeighbours(grid *Grid) *Grid {
+	growGridIfNeeded(grid)
+	if len(grid.counts) != grid.dimension {
+		grid.counts = make([][4][]uint64, grid.dimension)
+	}
+
+	parallelRows(grid.dimension, func(xStart, xEnd int) {
+		for x := xStart; x < xEnd; x++ {
+			if grid.boundary == BoundaryMirror {
+				grid.counts[x] = grid.countRowMirror(x)
+			} else {
+				grid.counts[x] = grid.countRowSWAR(x)
+			}
+		}
+	})
+	return grid
+}
+
+// neighbourRow returns row x's bit-packed state, wrapping around under
+// toroidal boundaries or returning an all-dead row past the grid's edge.
+func (g *Grid) neighbourRow(x int, wrap bool) []uint64 {
+	if wrap {
+		return g.rows[((x%g.dimension)+g.dimension)%g.dimension]
+	}
+	if x < 0 || x >= g.dimension {
+		return make([]uint64, g.wordsPerRow)
+	}
+	return g.rows[x]
+}
+
+// countRowSWAR computes row x's neighbour-count bit-planes using three-input
+// half/full-adder logic: each of the three relevant rows (above, current,
+// below) first contributes its west/east shifted copies (plus itself for
+// above/below, which count their whole 3-wide span; the current row counts
+// only west/east, since a cell is never its own neighbour), then those
+// per-row partial sums are combined through a small carry-save adder network
+// into a 4-bit total (0-8) encoded as 4 aligned bit-planes.
+func (g *Grid) countRowSWAR(x int) [4][]uint64 {
+	wrap := g.boundary == BoundaryToroidal
+	above := g.neighbourRow(x-1, wrap)
+	cur := g.rows[x]
+	below := g.neighbourRow(x+1, wrap)
+
+	aboveW, aboveE := westBitplane(above, wrap, g.dimension), eastBitplane(above, wrap, g.dimension)
+	curW, curE := westBitplane(cur, wrap, g.dimension), eastBitplane(cur, wrap, g.dimension)
+	belowW, belowE := westBitplane(below, wrap, g.dimension), eastBitplane(below, wrap, g.dimension)
+
+	s1, c1 := fullAddWords(aboveW, above, aboveE)
+	s2, c2 := halfAddWords(curW, curE)
+	s3, c3 := fullAddWords(belowW, below, belowE)
+
+	s4, c4 := fullAddWords(s1, s2, s3) // bit0 of the total
+	s5, c5 := halfAddWords(c1, c2)
+	s6, c6 := fullAddWords(c3, c4, s5) // bit1 of the total
+	s7, c7 := halfAddWords(c5, c6)     // bit2 (s7) and bit3 (c7) of the total
+
+	return [4][]uint64{s4, s6, s7, c7}
+}
+
+// countRowMirror computes row x's neighbour-count bit-planes cell by cell,
+// reflecting out-of-bounds coordinates back into the grid with reflectIndex.
+func (g *Grid) countRowMirror(x int) [4][]uint64 {
+	var planes [4][]uint64
+	for b := range planes {
+		planes[b] = make([]uint64, g.wordsPerRow)
+	}
+	for y := 0; y < g.dimension; y++ {
+		count := 0
+		for dx := -1; dx <= 1; dx++ {
+			for dy := -1; dy <= 1; dy++ {
+				if dx == 0 && dy == 0 {
+					continue
+				}
+				nx, ny := reflectIndex(x+dx, g.dimension), reflectIndex(y+dy, g.dimension)
+				if g.getBit(nx, ny) {
+					count++
+				}
+			}
+		}
+		word, bit := y/64, uint(y%64)
+		for b := 0; b < 4; b++ {
+			if count&(1<<b) != 0 {
+				planes[b][word] |= uint64(1) << bit
+			}
+		}
+	}
+	return planes
+}
+
+// westBitplane returns, for each column y, the state of column y-1 (0/dead
+// past the left edge, or wrapped from the right edge when wrap is set).
+func westBitplane(row []uint64, wrap bool, dimension int) []uint64 {
+	out := make([]uint64, len(row))
+	var carryIn uint64
+	if wrap && dimension > 0 {
+		lastWord, lastBit := (dimension-1)/64, uint((dimension-1)%64)
+		if row[lastWord]&(uint64(1)<<lastBit) != 0 {
+			carryIn = 1
+		}
+	}
+	for i := 0; i < len(row); i++ {
+		out[i] = (row[i] << 1) | carryIn
+		carryIn = row[i] >> 63
+	}
+	return out
+}
+
+// eastBitplane returns, for each column y, the state of column y+1 (0/dead
+// past the right edge, or wrapped from the left edge when wrap is set).
+func eastBitplane(row []uint64, wrap bool, dimension int) []uint64 {
+	out := make([]uint64, len(row))
+	var carryIn uint64
+	for i := len(row) - 1; i >= 0; i-- {
+		out[i] = (row[i] >> 1) | (carryIn << 63)
+		carryIn = row[i] & 1
+	}
+	if wrap && dimension > 0 {
+		// The generic shift above zero-fills the column just past the last
+		// valid bit, which may be in the middle of a partially-used word;
+		// patch that one bit to wrap to column 0 instead.
+		lastWord, lastBit := (dimension-1)/64, uint((dimension-1)%64)
+		mask := uint64(1) << lastBit
+		out[lastWord] &^= mask
+		if row[0]&1 != 0 {
+			out[lastWord] |= mask
+		}
+	}
+	return out
+}
+
+// fullAddWords computes, lane by lane, the sum and carry of three aligned
+// bit-planes: sum = a^b^c, carry = majority(a, b, c).
+func fullAddWords(a, b, c []uint64) (sum, carry []uint64) {
+	sum, carry = make([]uint64, len(a)), make([]uint64, len(a))
+	for i := range a {
+		sum[i] = a[i] ^ b[i] ^ c[i]
+		carry[i] = (a[i] & b[i]) | (b[i] & c[i]) | (a[i] & c[i])
+	}
+	return
+}
+
+// halfAddWords computes, lane by lane, the sum and carry of two aligned
+// bit-planes: sum = a^b, carry = a&b.
+func halfAddWords(a, b []uint64) (sum, carry []uint64) {
+	sum, carry = make([]uint64, len(a)), make([]uint64, len(a))
+	for i := range a {
+		sum[i] = a[i] ^ b[i]
+		carry[i] = a[i] & b[i]
+	}
+	return
+}
+
+func orWords(a, b []uint64) []uint64 {
+	out := make([]uint64, len(a))
+	for i := range a {
+		out[i] = a[i] | b[i]
+	}
+	return out
+}
+
+func andWords(a, b []uint64) []uint64 {
+	out := make([]uint64, len(a))
+	for i := range a {
+		out[i] = a[i] & b[i]
+	}
+	return out
+}
+
+func notWords(a []uint64) []uint64 {
+	out := make([]uint64, len(a))
+	for i := range a {
+		out[i] = ^a[i]
+	}
+	return out
+}
+
+// maskForCount returns, lane by lane, a bit-plane that is all-ones in every
+// lane whose 4-bit count (planes[0..3]) equals n, all-zero elsewhere.
+func maskForCount(planes [4][]uint64, n, wordsPerRow int) []uint64 {
+	mask := make([]uint64, wordsPerRow)
+	for i := range mask {
+		mask[i] = ^uint64(0)
+	}
+	for b := 0; b < 4; b++ {
+		plane := planes[b]
+		if (n>>b)&1 == 0 {
+			plane = notWords(plane)
+		}
+		mask = andWords(mask, plane)
+	}
+	return mask
+}
+
+// nextRow computes row x's next generation in parallel across all 64 lanes
+// of each word: next = (birth mask for this row's live rule) on dead cells,
+// OR (survive mask) on live cells, built by ORing together the per-count
+// masks the rule's Birth/Survive arrays select.
+func (g *Grid) nextRow(x int) []uint64 {
+	planes := g.counts[x]
+	birth := make([]uint64, g.wordsPerRow)
+	survive := make([]uint64, g.wordsPerRow)
+	for n := 0; n <= 8; n++ {
+		mask := maskForCount(planes, n, g.wordsPerRow)
+		if g.rule.Birth[n] {
+			birth = orWords(birth, mask)
+		}
+		if g.rule.Survive[n] {
+			survive = orWords(survive, mask)
+		}
+	}
+	cur := g.rows[x]
+	next := orWords(andWords(birth, notWords(cur)), andWords(survive, cur))
+	// Clear the unused high bits of the last word, so no cell is ever born
+	// past the grid's last column.
+	if extra := g.dimension % 64; extra != 0 {
+		next[g.wordsPerRow-1] &= uint64(1)<<uint(extra) - 1
+	}
+	return next
+}
+
+// updateGrid advances every row to its next generation using the counts
+// countNeighbours last computed, splitting the work across a worker pool the
+// same way countNeighbours does.
+func updateGrid(grid *Grid) *Grid {
+	next := make([][]uint64, grid.dimension)
+	parallelRows(grid.dimension, func(xStart, xEnd int) {
+		for x := xStart; x < xEnd; x++ {
+			next[x] = grid.nextRow(x)
+		}
+	})
+	grid.rows = next
+	return grid
+}
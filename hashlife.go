@@ -0,0 +1,323 @@
+package main
+
+// Node is one quadtree node of a Hashlife universe. Leaves (level 0) are one
+// of the two canonical singletons deadLeaf/aliveLeaf and carry no children;
+// every other node is the hash-consed union of four children one level
+// smaller, so two structurally identical subtrees are always the same *Node.
+type Node struct {
+	nw, ne, sw, se *Node
+	level          uint8
+	pop            uint64
+	hash           uint64
+	alive          bool  // meaningful only when level == 0
+	result         *Node // memoized: this node advanced 2^(level-2) generations; nil until first computed, level >= 2 only
+}
+
+// nodeKey identifies a node by its four (already-canonical) children, so the
+// hash-consing table collapses every structurally identical subtree onto one
+// *Node, making Step's memoization effective.
+type nodeKey struct {
+	nw, ne, sw, se *Node
+}
+
+// Rect is an axis-aligned region of cell coordinates, used to bound Render's descent.
+type Rect struct {
+	X0, Y0, X1, Y1 int
+}
+
+func (r Rect) intersects(x0, y0, x1, y1 int) bool {
+	return r.X0 < x1 && x0 < r.X1 && r.Y0 < y1 && y0 < r.Y1
+}
+
+// Engine is an alternative simulation backend to Grid's dense array: a
+// Hashlife quadtree that shares identical subtrees and memoizes their future,
+// so very large, sparse, or deeply-run patterns advance far faster than
+// recomputing every cell every generation.
+type Engine struct {
+	rule      Rule
+	table     map[nodeKey]*Node
+	deadLeaf  *Node
+	aliveLeaf *Node
+	root      *Node
+	originX   int // grid coordinate of root's (0, 0) corner, for re-centering as the root expands
+	originY   int
+}
+
+// NewEngine builds an empty Hashlife universe that will transition according to rule.
+func NewEngine(rule Rule) *Engine {
+	e := &Engine{
+		rule:  rule,
+		table: make(map[nodeKey]*Node),
+	}
+	e.deadLeaf = &Node{level: 0, alive: false, hash: 0}
+	e.aliveLeaf = &Node{level: 0, alive: true, pop: 1, hash: 1}
+	// A level-1 empty node as the minimal root; SeedFromGrid grows it to fit.
+	empty1 := e.intern(e.deadLeaf, e.deadLeaf, e.deadLeaf, e.deadLeaf)
+	e.root = empty1
+	return e
+}
+
+// intern returns the canonical node for the given four children, creating and
+// caching it on first use. Children must already be canonical.
+func (e *Engine) intern(nw, ne, sw, se *Node) *Node {
+	key := nodeKey{nw, ne, sw, se}
+	if n, ok := e.table[key]; ok {
+		return n
+	}
+	n := &Node{
+		nw: nw, ne: ne, sw: sw, se: se,
+		level: nw.level + 1,
+		pop:   nw.pop + ne.pop + sw.pop + se.pop,
+		hash:  combineHash(nw.hash, ne.hash, sw.hash, se.hash, nw.level+1),
+	}
+	e.table[key] = n
+	return n
+}
+
+func combineHash(a, b, c, d uint64, level uint8) uint64 {
+	h := uint64(14695981039346656037)
+	for _, v := range [...]uint64{a, b, c, d, uint64(level)} {
+		h ^= v
+		h *= 1099511628211
+	}
+	return h
+}
+
+// empty returns the canonical fully-dead node at the given level.
+func (e *Engine) empty(level uint8) *Node {
+	n := e.deadLeaf
+	for l := uint8(0); l < level; l++ {
+		n = e.intern(n, n, n, n)
+	}
+	return n
+}
+
+// expand doubles the universe by making n one of the four quadrants of a new,
+// one-level-larger node, bordered by empty space. The pattern keeps the same
+// absolute position; the caller is responsible for the origin shift this implies.
+func (e *Engine) expand(n *Node) *Node {
+	empty := e.empty(n.level - 1)
+	nw := e.intern(empty, empty, empty, n.nw)
+	ne := e.intern(empty, empty, n.ne, empty)
+	sw := e.intern(empty, n.sw, empty, empty)
+	se := e.intern(n.se, empty, empty, empty)
+	return e.intern(nw, ne, sw, se)
+}
+
+// SeedFromGrid replaces the engine's universe with the live cells of g. The
+// root is sized to the live cells' bounding box (plus a small margin), not
+// to g's full dimension, since every cell outside that box is dead anyway -
+// this is what lets Step grow the universe gradually like a normal Hashlife
+// run instead of starting from a needlessly huge root.
+func (e *Engine) SeedFromGrid(g *Grid) {
+	minX, minY, maxX, maxY := g.dimension, g.dimension, -1, -1
+	for i := 0; i < g.dimension; i++ {
+		for j := 0; j < g.dimension; j++ {
+			if !g.getBit(i, j) {
+				continue
+			}
+			minX, minY = min(minX, i), min(minY, j)
+			maxX, maxY = max(maxX, i), max(maxY, j)
+		}
+	}
+	if maxX < 0 {
+		e.originX, e.originY = 0, 0
+		e.root = e.empty(2)
+		return
+	}
+
+	// result() is only correct if the live region fits within the centre half
+	// of the root, so the root must be at least twice the pattern's span.
+	span := max(maxX-minX+1, maxY-minY+1)
+	level := uint8(2)
+	for (1 << level) < span*2 {
+		level++
+	}
+	size := 1 << level
+	e.originX = (minX+maxX)/2 - size/2
+	e.originY = (minY+maxY)/2 - size/2
+	e.root = e.buildRect(level, e.originX, e.originY, g)
+}
+
+// buildRect constructs the node of the given level covering absolute grid
+// cells [ox, ox+2^level) x [oy, oy+2^level), reading live state from g (cells
+// outside g's own bounds are treated as dead).
+func (e *Engine) buildRect(level uint8, ox, oy int, g *Grid) *Node {
+	if level == 0 {
+		x, y := ox, oy
+		if x >= 0 && x < g.dimension && y >= 0 && y < g.dimension && g.getBit(x, y) {
+			return e.aliveLeaf
+		}
+		return e.deadLeaf
+	}
+	half := 1 << (level - 1)
+	nw := e.buildRect(level-1, ox, oy, g)
+	ne := e.buildRect(level-1, ox+half, oy, g)
+	sw := e.buildRect(level-1, ox, oy+half, g)
+	se := e.buildRect(level-1, ox+half, oy+half, g)
+	return e.intern(nw, ne, sw, se)
+}
+
+// result returns n advanced by 2^(n.level-2) generations, a level-(n.level-1)
+// node covering the centre of n. Requires n.level >= 2. Results are memoized
+// on the node itself, so repeated or overlapping work across the quadtree is
+// computed only once.
+func (e *Engine) result(n *Node) *Node {
+	if n.result != nil {
+		return n.result
+	}
+	if n.level == 2 {
+		n.result = e.baseResult(n)
+		return n.result
+	}
+
+	// Nine overlapping level-(n.level-1) subnodes, built from the 4x4 grid of
+	// n's grandchildren so each subsequent quadrant overlaps its neighbours by half.
+	n00, n01, n02, n03 := n.nw.nw, n.nw.ne, n.ne.nw, n.ne.ne
+	n10, n11, n12, n13 := n.nw.sw, n.nw.se, n.ne.sw, n.ne.se
+	n20, n21, n22, n23 := n.sw.nw, n.sw.ne, n.se.nw, n.se.ne
+	n30, n31, n32, n33 := n.sw.sw, n.sw.se, n.se.sw, n.se.se
+
+	m00 := e.intern(n00, n01, n10, n11)
+	m01 := e.intern(n01, n02, n11, n12)
+	m02 := e.intern(n02, n03, n12, n13)
+	m10 := e.intern(n10, n11, n20, n21)
+	m11 := e.intern(n11, n12, n21, n22)
+	m12 := e.intern(n12, n13, n22, n23)
+	m20 := e.intern(n20, n21, n30, n31)
+	m21 := e.intern(n21, n22, n31, n32)
+	m22 := e.intern(n22, n23, n32, n33)
+
+	r00, r01, r02 := e.result(m00), e.result(m01), e.result(m02)
+	r10, r11, r12 := e.result(m10), e.result(m11), e.result(m12)
+	r20, r21, r22 := e.result(m20), e.result(m21), e.result(m22)
+
+	cnw := e.intern(r00, r01, r10, r11)
+	cne := e.intern(r01, r02, r11, r12)
+	csw := e.intern(r10, r11, r20, r21)
+	cse := e.intern(r11, r12, r21, r22)
+
+	n.result = e.intern(e.result(cnw), e.result(cne), e.result(csw), e.result(cse))
+	return n.result
+}
+
+// baseResult handles the level-2 (4x4 cell) base case directly, applying the
+// engine's rule to compute the centre 2x2 square one generation ahead.
+func (e *Engine) baseResult(n *Node) *Node {
+	var cells [4][4]bool
+	cells[0][0], cells[1][0], cells[0][1], cells[1][1] = n.nw.nw.alive, n.nw.ne.alive, n.nw.sw.alive, n.nw.se.alive
+	cells[2][0], cells[3][0], cells[2][1], cells[3][1] = n.ne.nw.alive, n.ne.ne.alive, n.ne.sw.alive, n.ne.se.alive
+	cells[0][2], cells[1][2], cells[0][3], cells[1][3] = n.sw.nw.alive, n.sw.ne.alive, n.sw.sw.alive, n.sw.se.alive
+	cells[2][2], cells[3][2], cells[2][3], cells[3][3] = n.se.nw.alive, n.se.ne.alive, n.se.sw.alive, n.se.se.alive
+
+	next := func(x, y int) *Node {
+		count := 0
+		for dx := -1; dx <= 1; dx++ {
+			for dy := -1; dy <= 1; dy++ {
+				if dx == 0 && dy == 0 {
+					continue
+				}
+				if cells[x+dx][y+dy] {
+					count++
+				}
+			}
+		}
+		alive := cells[x][y]
+		if alive && e.rule.Survive[count] || !alive && e.rule.Birth[count] {
+			return e.aliveLeaf
+		}
+		return e.deadLeaf
+	}
+
+	return e.intern(next(1, 1), next(2, 1), next(1, 2), next(2, 2))
+}
+
+// Step advances the universe by 2^pow generations, growing the root (and
+// shifting origin to keep it centred) so the memoized result is big enough.
+// pow must be reached gradually from 0 upward for the 2^pow figure to be
+// exact; if the root is already larger than pow+2 levels (e.g. because a
+// previous, larger Step already grew it, or livePopulationWithin below had to
+// pad further), Step still makes progress but may advance by more than 2^pow
+// generations.
+// Step returns the number of generations it actually advanced by, which is
+// 2^pow unless the root needed extra padding to keep every live cell within
+// the centre half (see the loop below), in which case it is larger.
+func (e *Engine) Step(pow int) int {
+	// result() is only correct while every live cell stays within the centre
+	// half of root; keep padding (growing the universe outward, the
+	// "infinite universe" behaviour) until that holds.
+	for e.root.pop > 0 {
+		size := 1 << e.root.level
+		// result() below advances size/4 generations and keeps only the
+		// centre half, so require live cells within the centre quarter,
+		// size/8 inside the centre half's edge: room for a pattern that
+		// spreads into empty space at most half a cell per generation, as
+		// under Conway's rule.
+		margin := size * 3 / 8
+		inner := Rect{X0: e.originX + margin, Y0: e.originY + margin, X1: e.originX + size - margin, Y1: e.originY + size - margin}
+		if e.livePopulationWithin(inner) == e.root.pop {
+			break
+		}
+		shift := 1 << (e.root.level - 1)
+		e.root = e.expand(e.root)
+		e.originX -= shift
+		e.originY -= shift
+	}
+
+	for int(e.root.level) < pow+2 {
+		shift := 1 << (e.root.level - 1) // half of root's current size
+		e.root = e.expand(e.root)
+		e.originX -= shift
+		e.originY -= shift
+	}
+	// result() covers the centre half of root, offset a quarter of root's
+	// size in from its origin.
+	quarter := 1 << (e.root.level - 2)
+	gens := quarter
+	e.root = e.result(e.root)
+	e.originX += quarter
+	e.originY += quarter
+	return gens
+}
+
+// livePopulationWithin counts live cells inside r, used to check whether the
+// live region has drifted close to root's edge.
+func (e *Engine) livePopulationWithin(r Rect) uint64 {
+	var count uint64
+	e.Render(r, func(x, y int) { count++ })
+	return count
+}
+
+// Render visits every live cell whose quadtree node intersects viewport
+// (in absolute grid coordinates), calling visit with its absolute position.
+// Dead subtrees and subtrees entirely outside viewport are skipped without
+// descending, which is what lets Render stay fast over mostly-empty universes.
+func (e *Engine) Render(viewport Rect, visit func(x, y int)) {
+	e.render(e.root, e.originX, e.originY, viewport, visit)
+}
+
+func (e *Engine) render(n *Node, x0, y0 int, viewport Rect, visit func(x, y int)) {
+	if n.pop == 0 {
+		return
+	}
+	size := 1 << n.level
+	if !viewport.intersects(x0, y0, x0+size, y0+size) {
+		return
+	}
+	if n.level == 0 {
+		if n.alive {
+			visit(x0, y0)
+		}
+		return
+	}
+	half := size / 2
+	e.render(n.nw, x0, y0, viewport, visit)
+	e.render(n.ne, x0+half, y0, viewport, visit)
+	e.render(n.sw, x0, y0+half, viewport, visit)
+	e.render(n.se, x0+half, y0+half, viewport, visit)
+}
+
+// Population returns the total number of live cells in the universe.
+func (e *Engine) Population() uint64 {
+	return e.root.pop
+}
@@ -0,0 +1,72 @@
+package main
+
+import "testing"
+
+// gridLiveCells returns the set of live cell coordinates in g.
+func gridLiveCells(g *Grid) map[[2]int]bool {
+	cells := make(map[[2]int]bool)
+	for x := 0; x < g.dimension; x++ {
+		for y := 0; y < g.dimension; y++ {
+			if g.getBit(x, y) {
+				cells[[2]int{x, y}] = true
+			}
+		}
+	}
+	return cells
+}
+
+// engineLiveCells returns the set of live cell coordinates e reports within viewport.
+func engineLiveCells(e *Engine, viewport Rect) map[[2]int]bool {
+	cells := make(map[[2]int]bool)
+	e.Render(viewport, func(x, y int) {
+		cells[[2]int{x, y}] = true
+	})
+	return cells
+}
+
+func cellSetsEqual(a, b map[[2]int]bool) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for c := range a {
+		if !b[c] {
+			return false
+		}
+	}
+	return true
+}
+
+// TestStepTracksReportedGenerations reproduces the review finding that
+// Step(0) can silently advance more than one generation: it drives a dense
+// reference grid forward by exactly the generation count Step(0) reports
+// and checks the two backends still agree cell-for-cell, so the returned
+// count is trustworthy for anyone (e.g. the HUD) relying on it.
+func TestStepTracksReportedGenerations(t *testing.T) {
+	const dim = 40
+	ref := newTestGridForEditor(dim)
+	glider := [][2]int{{1, 0}, {2, 1}, {0, 2}, {1, 2}, {2, 2}}
+	ox, oy := 15, 15
+	for _, c := range glider {
+		ref.setBit(ox+c[0], oy+c[1], true)
+	}
+	ref.rule = conwayRule
+	ref = countNeighbours(ref)
+
+	e := NewEngine(conwayRule)
+	e.SeedFromGrid(ref)
+
+	viewport := Rect{X0: 0, Y0: 0, X1: dim, Y1: dim}
+	for i := 0; i < 4; i++ {
+		gens := e.Step(0)
+		if gens <= 0 {
+			t.Fatalf("Step(0) reported %d generations, want > 0", gens)
+		}
+		for g := 0; g < gens; g++ {
+			stepSimulation(ref)
+		}
+		want, got := gridLiveCells(ref), engineLiveCells(e, viewport)
+		if !cellSetsEqual(want, got) {
+			t.Fatalf("after %d reported generation(s) (iteration %d): hashlife cells %v, dense reference cells %v", gens, i, got, want)
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestGridPopulation(t *testing.T) {
+	g := newTestGridForEditor(10)
+	for _, c := range [][2]int{{0, 0}, {3, 4}, {9, 9}} {
+		g.setBit(c[0], c[1], true)
+	}
+	if pop := gridPopulation(g); pop != 3 {
+		t.Errorf("gridPopulation() = %d, want 3", pop)
+	}
+}
+
+// TestGridDiffCountsBirthsAndDeaths checks gridDiff distinguishes cells that
+// turned on from cells that turned off between two generations' bit-packed
+// rows, rather than just counting population change.
+func TestGridDiffCountsBirthsAndDeaths(t *testing.T) {
+	prev := newTestGridForEditor(10)
+	prev.setBit(1, 1, true)
+	prev.setBit(2, 2, true)
+
+	next := newTestGridForEditor(10)
+	next.setBit(2, 2, true) // survives
+	next.setBit(5, 5, true) // born
+	// (1, 1) died.
+
+	births, deaths := gridDiff(prev.rows, next.rows)
+	if births != 1 {
+		t.Errorf("births = %d, want 1", births)
+	}
+	if deaths != 1 {
+		t.Errorf("deaths = %d, want 1", deaths)
+	}
+}
+
+// TestGridFingerprintStableAndSensitive checks gridFingerprint returns the
+// same hash for identical grid state and a different hash once a single
+// bit changes, the property runHeadless relies on to spot oscillators and
+// regressions.
+func TestGridFingerprintStableAndSensitive(t *testing.T) {
+	a := newTestGridForEditor(10)
+	a.setBit(1, 1, true)
+	b := newTestGridForEditor(10)
+	b.setBit(1, 1, true)
+
+	if gridFingerprint(a) != gridFingerprint(b) {
+		t.Error("gridFingerprint differs for identical grid state")
+	}
+
+	b.setBit(2, 2, true)
+	if gridFingerprint(a) == gridFingerprint(b) {
+		t.Error("gridFingerprint unchanged after a bit flipped")
+	}
+}
+
+// TestRunHeadlessWritesExpectedRows drives a blinker (period-2 oscillator)
+// for a few generations and checks the CSV output has the expected header
+// plus one row per generation, generation 0 included.
+func TestRunHeadlessWritesExpectedRows(t *testing.T) {
+	g := newTestGridForEditor(10)
+	g.rule = conwayRule
+	for _, c := range [][2]int{{3, 4}, {4, 4}, {5, 4}} { // horizontal blinker
+		g.setBit(c[0], c[1], true)
+	}
+	countNeighbours(g)
+
+	out := t.TempDir() + "/stats.csv"
+	if err := runHeadless(g, 2, out); err != nil {
+		t.Fatalf("runHeadless: %v", err)
+	}
+
+	data, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatalf("reading %s: %v", out, err)
+	}
+
+	lines := strings.Split(strings.TrimRight(string(data), "\n"), "\n")
+	if len(lines) != 4 { // header + generation 0, 1, 2
+		t.Fatalf("got %d lines, want 4:\n%s", len(lines), data)
+	}
+	if !strings.HasPrefix(lines[0], "generation,live,births,deaths,delta,fingerprint") {
+		t.Errorf("header = %q", lines[0])
+	}
+}
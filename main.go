@@ -1,8 +1,11 @@
 package main
 
 import (
+	"flag"
+	"fmt"
 	"math"
 	"math/rand/v2"
+	"os"
 	"strconv"
 	"time"
 
@@ -25,95 +28,37 @@ const (
 	startPaused     bool    = true  // start paused
 )
 
-type Cell struct {
-	x          int
-	y          int
-	state      bool // true = alive, false = dead
-	neighbours int
-}
-
-type Grid struct {
-	cells           [][]Cell
-	dimension       int
-	lifetime        int
-	paused          bool
-	tickRate        float32
-	initGrid        func(*Grid) *Grid
-	countNeighbours func(*Grid) *Grid
-	updateGrid      func(*Grid) *Grid
-	drawGrid        func(*Grid)
-}
-
-func initGrid(grid *Grid) *Grid {
-	grid.dimension = gridDimension
-	grid.lifetime = gridLifeTime
-	grid.tickRate = tickRate
-	grid.paused = startPaused
-	grid.cells = make([][]Cell, grid.dimension)
-	// Initialize the Grid with random values
-	for i := 0; i < grid.dimension; i++ {
-		grid.cells[i] = make([]Cell, grid.dimension)
-		for j := 0; j < grid.dimension; j++ {
-			grid.cells[i][j].x = i
-			grid.cells[i][j].y = j
-			if useRandom {
-				grid.cells[i][j].state = rand.Float32() < probability
-			} else {
-				grid.cells[i][j].state = false
-			}
-		}
-	}
-	return grid
-}
+var (
+	headlessFlag    = flag.Bool("headless", false, "run without opening a window, emitting per-generation statistics instead")
+	generationsFlag = flag.Int("generations", 100, "number of generations to run in headless mode")
+	seedFlag        = flag.Int64("seed", 0, "deterministic seed for random grid seeding; 0 seeds from the current time")
+	outFlag         = flag.String("out", "", "CSV file to write headless statistics to; empty writes to stdout")
+	patternFlag     = flag.String("pattern", "", "RLE file to seed the grid from, instead of random noise")
+)
 
-func countNeighbours(grid *Grid) *Grid {
-	// Count the number of live neigbours
-	for i := 0; i < grid.dimension; i++ {
-		for j := 0; j < grid.dimension; j++ {
-			liveNeighbours := 0
-			for x := i - 1; x <= i+1; x++ {
-				for y := j - 1; y <= j+1; y++ {
-					if x >= 0 && x < grid.dimension && y >= 0 && y < grid.dimension {
-						if grid.cells[x][y].state {
-							liveNeighbours++
-						}
-					}
-				}
-			}
-			// Subtract the cell itself from the count
-			if grid.cells[i][j].state {
-				liveNeighbours--
-			}
-			grid.cells[i][j].neighbours = liveNeighbours
-		}
-	}
-	return grid
+// stepSimulation advances grid by exactly one generation: applies the rule,
+// recounts neighbours for the following generation, and ticks down the
+// lifetime countdown. The windowed main loop steps the grid through this
+// function; runHeadless calls the same updateGrid and countNeighbours itself,
+// so that it can diff the rows between the two calls.
+func stepSimulation(grid *Grid) {
+	updateGrid(grid)
+	countNeighbours(grid)
+	grid.lifetime -= 1
 }
 
-func updateGrid(grid *Grid) *Grid {
-	// Update the Grid based on the rules of the Game of Life
-	for i := 0; i < grid.dimension; i++ {
-		for j := 0; j < grid.dimension; j++ {
-			currCell := grid.cells[i][j]
-			if currCell.state {
-				if currCell.neighbours < 2 || currCell.neighbours > 3 {
-					grid.cells[i][j].state = false
-				} else {
-					if currCell.neighbours == 3 || currCell.neighbours == 2 {
-						grid.cells[i][j].state = true
-					}
-				}
-			} else {
-				if currCell.neighbours == 3 {
-					grid.cells[i][j].state = true
-				}
-			}
-		}
+// loadSeedPattern opens path as an RLE file to seed the grid from, in place
+// of random noise.
+func loadSeedPattern(path string) (*Pattern, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
 	}
-	return grid
+	defer f.Close()
+	return LoadPatternRLE(f)
 }
 
-func drawGrid(grid *Grid) {
+func drawGrid(grid *Grid, selectedPattern string, editor *Editor) {
 	cyclesRemaining := grid.lifetime
 	cellSize := screenDimension / int32(gridDimension)
 	rl.BeginDrawing()
@@ -121,7 +66,7 @@ func drawGrid(grid *Grid) {
 
 	for i := 0; i < grid.dimension; i++ {
 		for j := 0; j < grid.dimension; j++ {
-			currentCell := grid.cells[i][j]
+			currentCell := grid.cellAt(i, j)
 			if currentCell.state {
 				rl.DrawRectangle(int32(currentCell.x)*cellSize, int32(currentCell.y)*cellSize, cellSize, cellSize, rl.Black)
 				if debug {
@@ -133,29 +78,115 @@ func drawGrid(grid *Grid) {
 		}
 	}
 
+	if editor.hasSelection {
+		x0, y0, x1, y1 := editor.selectionBounds()
+		rl.DrawRectangleLines(int32(x0)*cellSize, int32(y0)*cellSize, int32(x1-x0)*cellSize, int32(y1-y0)*cellSize, rl.Red)
+	}
+
 	rl.DrawText("Cycles Remaining: "+strconv.Itoa(cyclesRemaining), 10, int32(screenDimension)+10, 32, rl.Black)
+	rl.DrawText("Rule: "+grid.rule.String()+" (Tab: cycle)", 10, int32(screenDimension)+74, 20, rl.Black)
+	rl.DrawText("Boundary: "+grid.boundary.String()+" (B: cycle)", 10, int32(screenDimension)+96, 20, rl.Black)
+	if selectedPattern != "" {
+		rl.DrawText("Pattern: "+selectedPattern+" (L: cycle, Enter: stamp)", 10, int32(screenDimension)+42, 20, rl.Black)
+	}
+
+	toolName := "Paint"
+	if editor.tool == ToolErase {
+		toolName = "Erase"
+	}
+	rl.DrawText(fmt.Sprintf("Tool: %s (drag: paint, right-drag: erase, shift-drag: select)  Brush: %d ([/]: resize)", toolName, editor.brushSize),
+		10, int32(screenDimension)+118, 20, rl.Black)
+	rl.DrawText("Selection: Ctrl+C copy, Ctrl+V paste, Ctrl+R/F rotate/flip clipboard, Ctrl+Z/Ctrl+Y undo/redo", 10, int32(screenDimension)+140, 20, rl.Black)
+
 	rl.EndDrawing()
+}
+
+// drawHashlife renders the engine's live cells the same way drawGrid renders
+// a dense Grid, only visiting nodes that intersect the visible window.
+// lastStepGens is the generation count the most recent Step call actually
+// advanced by, which Step may round up past the requested power of two to
+// keep every live cell within its centre half; surfacing it here means a
+// single step or tick never silently jumps an unexplained number of
+// generations.
+func drawHashlife(e *Engine, cellSize int32, lastStepGens int) {
+	rl.BeginDrawing()
+	rl.ClearBackground(rl.RayWhite)
 
+	viewport := Rect{X0: 0, Y0: 0, X1: gridDimension, Y1: gridDimension}
+	e.Render(viewport, func(x, y int) {
+		rl.DrawRectangle(int32(x)*cellSize, int32(y)*cellSize, cellSize, cellSize, rl.Black)
+	})
+
+	rl.DrawText("Hashlife (pop: "+strconv.FormatUint(e.Population(), 10)+") - H: toggle back", 10, int32(screenDimension)+10, 32, rl.Black)
+	rl.DrawText("Last step: "+strconv.Itoa(lastStepGens)+" generation(s)", 10, int32(screenDimension)+42, 20, rl.Black)
+	rl.EndDrawing()
 }
 
 func main() {
-	rl.InitWindow(screenDimension, screenDimension+50, "Game of Life")
+	flag.Parse()
+
+	grid := new(Grid)
+	if *seedFlag != 0 {
+		grid.rng = rand.New(rand.NewPCG(uint64(*seedFlag), uint64(*seedFlag)))
+	}
+	if *patternFlag != "" {
+		p, err := loadSeedPattern(*patternFlag)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "game of life:", err)
+			os.Exit(1)
+		}
+		grid.seedPattern = p
+	}
+	grid = initGrid(grid)
+	grid = countNeighbours(grid) // work out neighbours for initial seed
+
+	if *headlessFlag {
+		if err := runHeadless(grid, *generationsFlag, *outFlag); err != nil {
+			fmt.Fprintln(os.Stderr, "game of life:", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	rl.InitWindow(screenDimension, screenDimension+170, "Game of Life")
 	defer rl.CloseWindow()
 	rl.SetTargetFPS(120)
 
-	grid := initGrid(new(Grid))
-
-	// work out neighbours for initial seed
-	grid = countNeighbours(grid)
 	cellSize := float64(screenDimension / int32(gridDimension))
 
+	// pattern picker: L cycles the selected bundled pattern, Enter stamps it
+	// at the cursor.
+	patternNames := bundledPatternNames()
+	patternIndex := 0
+
+	// rule cycling: Tab steps through namedRules.
+	ruleIndex := 0
+
+	// boundary mode cycling: B steps through boundaryModes.
+	boundaryIndex := 0
+
+	// hashlife backend: H toggles between the dense Grid above and a
+	// Hashlife quadtree engine, which trades per-cell simplicity for vastly
+	// better scaling on large, sparse, long-running patterns.
+	hashlifeMode := false
+	engine := NewEngine(grid.rule)
+	lastStepGens := 0 // generations the most recent Step call actually advanced by, shown in the HUD
+
+	// editor: drag to paint/erase, shift-drag to select, Ctrl+C/V to
+	// copy/paste, Ctrl+Z/Y to undo/redo. Ctrl+R and F rotate and flip the
+	// clipboard; plain R is kept bound to resetting the grid below.
+	editor := NewEditor(grid)
+
 	// main loop
 	for !rl.WindowShouldClose() {
+		ctrlDown := rl.IsKeyDown(rl.KeyLeftControl) || rl.IsKeyDown(rl.KeyRightControl)
+		shiftDown := rl.IsKeyDown(rl.KeyLeftShift) || rl.IsKeyDown(rl.KeyRightShift)
+
 		if grid.lifetime <= 0 {
 			grid = initGrid(grid)
 			grid = countNeighbours(grid)
 		}
-		if rl.IsKeyPressed(rl.KeyR) {
+		if rl.IsKeyPressed(rl.KeyR) && !ctrlDown {
 			grid = initGrid(grid)
 			grid = countNeighbours(grid)
 		}
@@ -176,30 +207,140 @@ func main() {
 		}
 
 		mousePos := rl.GetMousePosition()
-		// calculate cell position
-		if rl.IsMouseButtonPressed(rl.MouseLeftButton) {
-			x := int(math.Floor(float64(mousePos.X) / cellSize))
-			y := int(math.Floor(float64(mousePos.Y) / cellSize))
-			grid.cells[x][y].state = !grid.cells[x][y].state
-			grid = countNeighbours(grid)
+		cellX := int(math.Floor(float64(mousePos.X) / cellSize))
+		cellY := int(math.Floor(float64(mousePos.Y) / cellSize))
+
+		switch {
+		case rl.IsMouseButtonPressed(rl.MouseLeftButton) && shiftDown:
+			editor.StartSelection(cellX, cellY)
+		case rl.IsMouseButtonPressed(rl.MouseLeftButton):
+			editor.ClearSelection()
+			editor.StartDrag(ToolPaint)
+		case rl.IsMouseButtonPressed(rl.MouseRightButton):
+			editor.ClearSelection()
+			editor.StartDrag(ToolErase)
+		}
+		if editor.selecting {
+			editor.UpdateSelection(cellX, cellY)
+		} else {
+			editor.PaintAt(cellX, cellY)
+		}
+		if rl.IsMouseButtonReleased(rl.MouseLeftButton) {
+			if editor.selecting {
+				editor.EndSelection()
+			} else {
+				editor.EndDrag()
+			}
+		}
+		if rl.IsMouseButtonReleased(rl.MouseRightButton) {
+			editor.EndDrag()
+		}
+
+		if ctrlDown && rl.IsKeyPressed(rl.KeyC) {
+			editor.Copy()
+		}
+		if ctrlDown && rl.IsKeyPressed(rl.KeyV) {
+			editor.Paste(cellX, cellY)
+		}
+		if ctrlDown && rl.IsKeyPressed(rl.KeyZ) {
+			editor.Undo()
+		}
+		if ctrlDown && rl.IsKeyPressed(rl.KeyY) {
+			editor.Redo()
+		}
+		if ctrlDown && rl.IsKeyPressed(rl.KeyR) {
+			editor.RotateClipboard()
+		}
+		if rl.IsKeyPressed(rl.KeyF) {
+			editor.FlipClipboard()
+		}
+		if rl.IsKeyPressed(rl.KeyLeftBracket) {
+			editor.AdjustBrush(-1)
+		}
+		if rl.IsKeyPressed(rl.KeyRightBracket) {
+			editor.AdjustBrush(1)
+		}
+
+		if rl.IsKeyPressed(rl.KeyL) && len(patternNames) > 0 {
+			patternIndex = (patternIndex + 1) % len(patternNames)
+		}
+
+		if rl.IsKeyPressed(rl.KeyTab) {
+			ruleIndex = (ruleIndex + 1) % len(namedRules)
+			rule, err := ParseRulestring(namedRules[ruleIndex].rule)
+			if err == nil {
+				grid.rule = rule
+			}
+		}
+
+		if rl.IsKeyPressed(rl.KeyB) {
+			boundaryIndex = (boundaryIndex + 1) % len(boundaryModes)
+			grid.boundary = boundaryModes[boundaryIndex]
+		}
+
+		var selectedPattern string
+		if len(patternNames) > 0 {
+			selectedPattern = patternNames[patternIndex]
+		}
+
+		if rl.IsKeyPressed(rl.KeyEnter) && selectedPattern != "" {
+			if p, err := loadBundledPattern(selectedPattern); err == nil {
+				x := int(math.Floor(float64(mousePos.X) / cellSize))
+				y := int(math.Floor(float64(mousePos.Y) / cellSize))
+				StampPattern(grid, p, x, y)
+				grid = countNeighbours(grid)
+			}
+		}
+
+		if rl.IsKeyPressed(rl.KeyH) {
+			if !hashlifeMode {
+				engine.rule = grid.rule
+				engine.SeedFromGrid(grid)
+			} else {
+				syncGridFromEngine(grid, engine)
+			}
+			hashlifeMode = !hashlifeMode
 		}
 
 		if grid.paused && rl.IsKeyPressed(rl.KeyRight) {
-			updateGrid(grid)
-			countNeighbours(grid)
-			grid.lifetime -= 1
+			if hashlifeMode {
+				lastStepGens = engine.Step(0)
+			} else {
+				stepSimulation(grid)
+			}
 		}
 
-		drawGrid(grid)
+		if hashlifeMode {
+			drawHashlife(engine, int32(cellSize), lastStepGens)
+		} else {
+			drawGrid(grid, selectedPattern, editor)
+		}
 
 		time.Sleep(time.Millisecond * time.Duration(grid.tickRate))
 
 		// update grid
 		if !grid.paused {
-			updateGrid(grid)
-			countNeighbours(grid)
-			grid.lifetime -= 1
+			if hashlifeMode {
+				lastStepGens = engine.Step(0)
+			} else {
+				stepSimulation(grid)
+			}
 		}
 
 	}
 }
+
+// syncGridFromEngine overwrites grid's cells with the engine's live cells
+// that fall within the dense grid's fixed bounds, so toggling back to the
+// classic backend picks up wherever the Hashlife engine left off.
+func syncGridFromEngine(grid *Grid, e *Engine) {
+	for i := 0; i < grid.dimension; i++ {
+		for j := 0; j < grid.dimension; j++ {
+			grid.setBit(i, j, false)
+		}
+	}
+	e.Render(Rect{X0: 0, Y0: 0, X1: grid.dimension, Y1: grid.dimension}, func(x, y int) {
+		grid.setBit(x, y, true)
+	})
+	countNeighbours(grid)
+}
@@ -0,0 +1,301 @@
+package main
+
+import (
+	"bufio"
+	"embed"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+//go:embed patterns/*.rle
+var bundledPatterns embed.FS
+
+// Pattern is a named collection of live cells, relative to its own (0, 0)
+// origin, as loaded from an RLE or Life 1.06 file.
+type Pattern struct {
+	Name   string
+	Width  int
+	Height int
+	Rule   string // rulestring from the RLE header, e.g. "B3/S23"; empty if unspecified
+	Cells  [][2]int
+}
+
+// LoadPatternRLE reads a pattern in the standard Run Length Encoded format:
+// a header line "x = W, y = H, rule = B3/S23" followed by a body of run
+// counts and tags (b = dead, o = alive, $ = end of line), terminated by "!".
+// Lines beginning with "#" are comments; a "#N Name" comment supplies Pattern.Name.
+func LoadPatternRLE(r io.Reader) (*Pattern, error) {
+	p := &Pattern{}
+	scanner := bufio.NewScanner(r)
+	headerFound := false
+	var body strings.Builder
+
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		if strings.HasPrefix(line, "#") {
+			if strings.HasPrefix(line, "#N ") {
+				p.Name = strings.TrimSpace(line[3:])
+			}
+			continue
+		}
+		if !headerFound {
+			if err := parseRLEHeader(line, p); err != nil {
+				return nil, err
+			}
+			headerFound = true
+			continue
+		}
+		body.WriteString(line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if !headerFound {
+		return nil, fmt.Errorf("patterns: RLE header not found")
+	}
+
+	x, y := 0, 0
+	runCount := 0
+	for _, r := range body.String() {
+		switch {
+		case r >= '0' && r <= '9':
+			runCount = runCount*10 + int(r-'0')
+		case r == 'b':
+			x += max(runCount, 1)
+			runCount = 0
+		case r == 'o':
+			n := max(runCount, 1)
+			for i := 0; i < n; i++ {
+				p.Cells = append(p.Cells, [2]int{x, y})
+				x++
+			}
+			runCount = 0
+		case r == '$':
+			y += max(runCount, 1)
+			x = 0
+			runCount = 0
+		case r == '!':
+			return p, nil
+		default:
+			return nil, fmt.Errorf("patterns: unexpected character %q in RLE body", r)
+		}
+	}
+	return nil, fmt.Errorf("patterns: RLE body missing terminating '!'")
+}
+
+func parseRLEHeader(line string, p *Pattern) error {
+	for _, field := range strings.Split(line, ",") {
+		kv := strings.SplitN(field, "=", 2)
+		if len(kv) != 2 {
+			return fmt.Errorf("patterns: malformed RLE header field %q", field)
+		}
+		key := strings.TrimSpace(kv[0])
+		value := strings.TrimSpace(kv[1])
+		switch key {
+		case "x":
+			w, err := strconv.Atoi(value)
+			if err != nil {
+				return fmt.Errorf("patterns: bad width in RLE header: %w", err)
+			}
+			p.Width = w
+		case "y":
+			h, err := strconv.Atoi(value)
+			if err != nil {
+				return fmt.Errorf("patterns: bad height in RLE header: %w", err)
+			}
+			p.Height = h
+		case "rule":
+			p.Rule = value
+		}
+	}
+	if p.Width == 0 && p.Height == 0 {
+		return fmt.Errorf("patterns: RLE header missing x/y dimensions")
+	}
+	return nil
+}
+
+// LoadPatternLife106 reads a pattern in the plain-text Life 1.06 format:
+// an optional "#Life 1.06" header line followed by one "x y" coordinate pair
+// of live cells per line. The block-based Life 1.05 format ("#P" blocks of
+// '.' and '*' rows) is not parsed; its cell rows are rejected as malformed.
+func LoadPatternLife106(r io.Reader) (*Pattern, error) {
+	p := &Pattern{}
+	scanner := bufio.NewScanner(r)
+	minX, minY := 0, 0
+	maxX, maxY := 0, 0
+	first := true
+
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			return nil, fmt.Errorf("patterns: malformed Life 1.06 line %q", line)
+		}
+		x, err := strconv.Atoi(fields[0])
+		if err != nil {
+			return nil, fmt.Errorf("patterns: bad x coordinate: %w", err)
+		}
+		y, err := strconv.Atoi(fields[1])
+		if err != nil {
+			return nil, fmt.Errorf("patterns: bad y coordinate: %w", err)
+		}
+		p.Cells = append(p.Cells, [2]int{x, y})
+		if first || x < minX {
+			minX = x
+		}
+		if first || y < minY {
+			minY = y
+		}
+		if first || x > maxX {
+			maxX = x
+		}
+		if first || y > maxY {
+			maxY = y
+		}
+		first = false
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if len(p.Cells) == 0 {
+		return nil, fmt.Errorf("patterns: Life 1.06 file contains no live cells")
+	}
+
+	// Life 1.06 coordinates may be negative and are not bounded by a header,
+	// so normalise them to a (0, 0) origin like the RLE loader produces.
+	for i, c := range p.Cells {
+		p.Cells[i] = [2]int{c[0] - minX, c[1] - minY}
+	}
+	p.Width = maxX - minX + 1
+	p.Height = maxY - minY + 1
+	return p, nil
+}
+
+// SavePatternRLE writes the live cells of g in RLE format, including the
+// current rule in the header so the file round-trips through LoadPatternRLE.
+func SavePatternRLE(w io.Writer, g *Grid) error {
+	if _, err := fmt.Fprintf(w, "x = %d, y = %d, rule = %s\n", g.dimension, g.dimension, g.rule.String()); err != nil {
+		return err
+	}
+
+	var body strings.Builder
+	lineLen := 0
+	writeTag := func(count int, tag byte) {
+		if count == 0 {
+			return
+		}
+		var s string
+		if count == 1 {
+			s = string(tag)
+		} else {
+			s = strconv.Itoa(count) + string(tag)
+		}
+		body.WriteString(s)
+		lineLen += len(s)
+	}
+
+	for j := 0; j < g.dimension; j++ {
+		runTag := byte(0)
+		runLen := 0
+		for i := 0; i < g.dimension; i++ {
+			tag := byte('b')
+			if g.getBit(i, j) {
+				tag = 'o'
+			}
+			if tag == runTag {
+				runLen++
+				continue
+			}
+			writeTag(runLen, runTag)
+			runTag, runLen = tag, 1
+		}
+		if runTag == 'o' {
+			writeTag(runLen, runTag)
+		}
+		body.WriteString("$")
+	}
+	body.WriteString("!")
+
+	_, err := io.WriteString(w, wrapRLEBody(body.String(), 70))
+	return err
+}
+
+// wrapRLEBody folds an RLE body into lines no longer than width, matching the
+// convention used by most RLE files in the wild.
+func wrapRLEBody(body string, width int) string {
+	var out strings.Builder
+	for len(body) > width {
+		out.WriteString(body[:width])
+		out.WriteString("\n")
+		body = body[width:]
+	}
+	out.WriteString(body)
+	out.WriteString("\n")
+	return out.String()
+}
+
+// StampPattern sets every live cell of p onto g with its origin placed at
+// (x, y), clipping any cells that fall outside the grid.
+func StampPattern(g *Grid, p *Pattern, x, y int) {
+	for _, c := range p.Cells {
+		px, py := x+c[0], y+c[1]
+		if px < 0 || px >= g.dimension || py < 0 || py >= g.dimension {
+			continue
+		}
+		g.setBit(px, py, true)
+	}
+}
+
+// RotatePattern90 returns a copy of p rotated 90 degrees clockwise about its
+// own origin.
+func RotatePattern90(p *Pattern) *Pattern {
+	rotated := &Pattern{Name: p.Name, Rule: p.Rule, Width: p.Height, Height: p.Width, Cells: make([][2]int, len(p.Cells))}
+	for i, c := range p.Cells {
+		rotated.Cells[i] = [2]int{p.Height - 1 - c[1], c[0]}
+	}
+	return rotated
+}
+
+// FlipPatternHorizontal returns a copy of p mirrored left-to-right about its
+// own origin.
+func FlipPatternHorizontal(p *Pattern) *Pattern {
+	flipped := &Pattern{Name: p.Name, Rule: p.Rule, Width: p.Width, Height: p.Height, Cells: make([][2]int, len(p.Cells))}
+	for i, c := range p.Cells {
+		flipped.Cells[i] = [2]int{p.Width - 1 - c[0], c[1]}
+	}
+	return flipped
+}
+
+// bundledPatternNames lists the embedded patterns available to the in-game
+// picker, sorted for a stable cycling order.
+func bundledPatternNames() []string {
+	entries, err := bundledPatterns.ReadDir("patterns")
+	if err != nil {
+		return nil
+	}
+	names := make([]string, 0, len(entries))
+	for _, e := range entries {
+		names = append(names, strings.TrimSuffix(e.Name(), ".rle"))
+	}
+	sort.Strings(names)
+	return names
+}
+
+// loadBundledPattern loads one of the patterns embedded under patterns/ by name.
+func loadBundledPattern(name string) (*Pattern, error) {
+	f, err := bundledPatterns.Open("patterns/" + name + ".rle")
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return LoadPatternRLE(f)
+}
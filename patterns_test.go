@@ -0,0 +1,219 @@
+package main
+
+import (
+	"sort"
+	"strings"
+	"testing"
+)
+
+func cellSet(cells [][2]int) map[[2]int]bool {
+	set := make(map[[2]int]bool, len(cells))
+	for _, c := range cells {
+		set[c] = true
+	}
+	return set
+}
+
+// TestLoadPatternRLEGlider parses a standard RLE glider and checks its
+// header fields and live cells are decoded correctly.
+func TestLoadPatternRLEGlider(t *testing.T) {
+	const rle = "#N Glider\n" +
+		"x = 3, y = 3, rule = B3/S23\n" +
+		"bob$2bo$3o!\n"
+
+	p, err := LoadPatternRLE(strings.NewReader(rle))
+	if err != nil {
+		t.Fatalf("LoadPatternRLE: %v", err)
+	}
+	if p.Name != "Glider" {
+		t.Errorf("Name = %q, want %q", p.Name, "Glider")
+	}
+	if p.Width != 3 || p.Height != 3 {
+		t.Errorf("dimensions = %dx%d, want 3x3", p.Width, p.Height)
+	}
+	if p.Rule != "B3/S23" {
+		t.Errorf("Rule = %q, want %q", p.Rule, "B3/S23")
+	}
+	want := cellSet([][2]int{{1, 0}, {2, 1}, {0, 2}, {1, 2}, {2, 2}})
+	got := cellSet(p.Cells)
+	if len(got) != len(want) {
+		t.Fatalf("Cells = %v, want %v", p.Cells, want)
+	}
+	for c := range want {
+		if !got[c] {
+			t.Errorf("missing cell %v in %v", c, p.Cells)
+		}
+	}
+}
+
+// TestLoadPatternRLERunLengths checks runs longer than one in both the
+// dead ('b') and alive ('o') tags, and a multi-line body.
+func TestLoadPatternRLERunLengths(t *testing.T) {
+	const rle = "x = 5, y = 2, rule = B3/S23\n3o2b$5o!"
+	p, err := LoadPatternRLE(strings.NewReader(rle))
+	if err != nil {
+		t.Fatalf("LoadPatternRLE: %v", err)
+	}
+	want := cellSet([][2]int{
+		{0, 0}, {1, 0}, {2, 0},
+		{0, 1}, {1, 1}, {2, 1}, {3, 1}, {4, 1},
+	})
+	got := cellSet(p.Cells)
+	if len(got) != len(want) {
+		t.Fatalf("Cells = %v, want %v", p.Cells, want)
+	}
+	for c := range want {
+		if !got[c] {
+			t.Errorf("missing cell %v in %v", c, p.Cells)
+		}
+	}
+}
+
+func TestLoadPatternRLEErrors(t *testing.T) {
+	cases := []struct {
+		name, rle string
+	}{
+		{"missing header", "bob$2bo$3o!"},
+		{"missing terminator", "x = 3, y = 3\nbob$2bo$3o"},
+		{"bad character", "x = 1, y = 1\nz!"},
+		{"malformed header field", "x 3, y = 3\nb!"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if _, err := LoadPatternRLE(strings.NewReader(c.rle)); err == nil {
+				t.Fatalf("LoadPatternRLE(%q) = nil error, want error", c.rle)
+			}
+		})
+	}
+}
+
+// TestLoadPatternLife106 checks coordinate normalisation to a (0, 0) origin,
+// including negative input coordinates.
+func TestLoadPatternLife106(t *testing.T) {
+	const life106 = "#Life 1.06\n-1 -1\n0 0\n1 -1\n"
+	p, err := LoadPatternLife106(strings.NewReader(life106))
+	if err != nil {
+		t.Fatalf("LoadPatternLife106: %v", err)
+	}
+	want := cellSet([][2]int{{0, 0}, {1, 1}, {2, 0}})
+	got := cellSet(p.Cells)
+	if len(got) != len(want) {
+		t.Fatalf("Cells = %v, want %v", p.Cells, want)
+	}
+	for c := range want {
+		if !got[c] {
+			t.Errorf("missing cell %v in %v", c, p.Cells)
+		}
+	}
+	if p.Width != 3 || p.Height != 2 {
+		t.Errorf("dimensions = %dx%d, want 3x2", p.Width, p.Height)
+	}
+}
+
+func TestLoadPatternLife106Errors(t *testing.T) {
+	cases := []struct {
+		name, body string
+	}{
+		{"no live cells", "#Life 1.06\n"},
+		{"malformed line", "1 2 3\n"},
+		{"non-numeric coordinate", "a b\n"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if _, err := LoadPatternLife106(strings.NewReader(c.body)); err == nil {
+				t.Fatalf("LoadPatternLife106(%q) = nil error, want error", c.body)
+			}
+		})
+	}
+}
+
+// TestSavePatternRLERoundTrips stamps a pattern onto a grid, saves it, and
+// reloads it, checking the live cells (relative to their bounding box) are
+// unchanged and the rule string round-trips.
+func TestSavePatternRLERoundTrips(t *testing.T) {
+	g := newTestGridForEditor(10)
+	g.rule = conwayRule
+	glider := [][2]int{{1, 0}, {2, 1}, {0, 2}, {1, 2}, {2, 2}}
+	for _, c := range glider {
+		g.setBit(c[0], c[1], true)
+	}
+
+	var sb strings.Builder
+	if err := SavePatternRLE(&sb, g); err != nil {
+		t.Fatalf("SavePatternRLE: %v", err)
+	}
+
+	reloaded, err := LoadPatternRLE(strings.NewReader(sb.String()))
+	if err != nil {
+		t.Fatalf("LoadPatternRLE(saved output): %v", err)
+	}
+	if reloaded.Rule != conwayRule.String() {
+		t.Errorf("Rule = %q, want %q", reloaded.Rule, conwayRule.String())
+	}
+	want := cellSet(glider)
+	got := cellSet(reloaded.Cells)
+	if len(got) != len(want) {
+		t.Fatalf("Cells = %v, want %v", reloaded.Cells, want)
+	}
+	for c := range want {
+		if !got[c] {
+			t.Errorf("missing cell %v in %v", c, reloaded.Cells)
+		}
+	}
+}
+
+// TestStampPatternClipsOutOfRange checks StampPattern silently drops cells
+// that fall outside the destination grid instead of panicking.
+func TestStampPatternClipsOutOfRange(t *testing.T) {
+	g := newTestGridForEditor(5)
+	p := &Pattern{Width: 2, Height: 2, Cells: [][2]int{{0, 0}, {1, 1}}}
+	StampPattern(g, p, 4, 4)
+
+	if !g.getBit(4, 4) {
+		t.Error("in-range cell (4,4) was not stamped")
+	}
+	// (5, 5) falls outside the 5x5 grid and must be clipped, not panic.
+}
+
+func TestRotatePattern90(t *testing.T) {
+	p := &Pattern{Width: 2, Height: 3, Cells: [][2]int{{0, 0}}}
+	rotated := RotatePattern90(p)
+	if rotated.Width != 3 || rotated.Height != 2 {
+		t.Errorf("dimensions = %dx%d, want 3x2", rotated.Width, rotated.Height)
+	}
+	want := [2]int{2, 0}
+	if rotated.Cells[0] != want {
+		t.Errorf("Cells[0] = %v, want %v", rotated.Cells[0], want)
+	}
+}
+
+func TestFlipPatternHorizontal(t *testing.T) {
+	p := &Pattern{Width: 4, Height: 1, Cells: [][2]int{{0, 0}, {3, 0}}}
+	flipped := FlipPatternHorizontal(p)
+	want := cellSet([][2]int{{3, 0}, {0, 0}})
+	got := cellSet(flipped.Cells)
+	if len(got) != len(want) {
+		t.Fatalf("Cells = %v, want %v", flipped.Cells, want)
+	}
+	for c := range want {
+		if !got[c] {
+			t.Errorf("missing cell %v in %v", c, flipped.Cells)
+		}
+	}
+}
+
+// TestBundledPatternNamesSorted checks the embedded pattern list is
+// non-empty and already in the sorted order loadBundledPattern's callers
+// rely on for stable cycling.
+func TestBundledPatternNamesSorted(t *testing.T) {
+	names := bundledPatternNames()
+	if len(names) == 0 {
+		t.Fatal("bundledPatternNames() returned no patterns")
+	}
+	if !sort.StringsAreSorted(names) {
+		t.Errorf("bundledPatternNames() = %v, not sorted", names)
+	}
+	if _, err := loadBundledPattern(names[0]); err != nil {
+		t.Errorf("loadBundledPattern(%q): %v", names[0], err)
+	}
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Rule is a Game of Life transition rule in B/S (birth/survival) notation:
+// Birth[n] is true if a dead cell with n live neighbours becomes alive, and
+// Survive[n] is true if a live cell with n live neighbours stays alive.
+type Rule struct {
+	Birth   [9]bool
+	Survive [9]bool
+}
+
+// conwayRule is the standard B3/S23 rule this simulator has always run.
+var conwayRule = Rule{
+	Birth:   [9]bool{3: true},
+	Survive: [9]bool{2: true, 3: true},
+}
+
+// namedRules lists the built-in rulestrings cycled by the hotkey, in order.
+var namedRules = []struct {
+	name string
+	rule string
+}{
+	{"Conway's Life", "B3/S23"},
+	{"HighLife", "B36/S23"},
+	{"Day & Night", "B3678/S34678"},
+	{"Seeds", "B2/S"},
+	{"Maze", "B3/S12345"},
+}
+
+// ParseRulestring parses the standard "B<digits>/S<digits>" notation (and the
+// equivalent legacy "<survive>/<birth>" form, e.g. "23/3") used across the
+// wider cellular automaton ecosystem.
+func ParseRulestring(s string) (Rule, error) {
+	s = strings.TrimSpace(s)
+	var rule Rule
+
+	if strings.ContainsAny(s, "Bb") || strings.ContainsAny(s, "Ss") {
+		parts := strings.SplitN(s, "/", 2)
+		if len(parts) != 2 {
+			return Rule{}, fmt.Errorf("rules: rulestring %q missing '/'", s)
+		}
+		bPart, sPart := parts[0], parts[1]
+		if strings.HasPrefix(sPart, "B") || strings.HasPrefix(sPart, "b") {
+			bPart, sPart = sPart, bPart
+		}
+		bPart = strings.TrimPrefix(strings.TrimPrefix(bPart, "B"), "b")
+		sPart = strings.TrimPrefix(strings.TrimPrefix(sPart, "S"), "s")
+
+		if err := setDigits(&rule.Birth, bPart); err != nil {
+			return Rule{}, fmt.Errorf("rules: rulestring %q: %w", s, err)
+		}
+		if err := setDigits(&rule.Survive, sPart); err != nil {
+			return Rule{}, fmt.Errorf("rules: rulestring %q: %w", s, err)
+		}
+		return rule, nil
+	}
+
+	// Legacy "survive/birth" notation, e.g. "23/3".
+	parts := strings.SplitN(s, "/", 2)
+	if len(parts) != 2 {
+		return Rule{}, fmt.Errorf("rules: rulestring %q is neither B/S nor survive/birth notation", s)
+	}
+	if err := setDigits(&rule.Survive, parts[0]); err != nil {
+		return Rule{}, fmt.Errorf("rules: rulestring %q: %w", s, err)
+	}
+	if err := setDigits(&rule.Birth, parts[1]); err != nil {
+		return Rule{}, fmt.Errorf("rules: rulestring %q: %w", s, err)
+	}
+	return rule, nil
+}
+
+// setDigits marks counts[d] true for every digit d appearing in s.
+func setDigits(counts *[9]bool, s string) error {
+	for _, r := range s {
+		d, err := strconv.Atoi(string(r))
+		if err != nil {
+			return fmt.Errorf("non-digit neighbour count %q", r)
+		}
+		if d < 0 || d > 8 {
+			return fmt.Errorf("neighbour count %d out of range 0-8", d)
+		}
+		counts[d] = true
+	}
+	return nil
+}
+
+// String renders the rule back into canonical "B.../S..." notation.
+func (r Rule) String() string {
+	var b, s strings.Builder
+	for n := 0; n <= 8; n++ {
+		if r.Birth[n] {
+			b.WriteString(strconv.Itoa(n))
+		}
+		if r.Survive[n] {
+			s.WriteString(strconv.Itoa(n))
+		}
+	}
+	return "B" + b.String() + "/S" + s.String()
+}
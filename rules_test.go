@@ -0,0 +1,90 @@
+package main
+
+import "testing"
+
+// TestParseRulestringBSForm checks the canonical "B<digits>/S<digits>" form,
+// in both orderings RLE headers are seen to use it in the wild.
+func TestParseRulestringBSForm(t *testing.T) {
+	cases := []struct {
+		name, s     string
+		wantBirth   []int
+		wantSurvive []int
+	}{
+		{"B before S", "B3/S23", []int{3}, []int{2, 3}},
+		{"S before B", "S23/B3", []int{3}, []int{2, 3}},
+		{"HighLife", "B36/S23", []int{3, 6}, []int{2, 3}},
+		{"empty survive", "B2/S", []int{2}, nil},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			rule, err := ParseRulestring(c.s)
+			if err != nil {
+				t.Fatalf("ParseRulestring(%q): %v", c.s, err)
+			}
+			for n := 0; n <= 8; n++ {
+				wantB := contains(c.wantBirth, n)
+				if rule.Birth[n] != wantB {
+					t.Errorf("Birth[%d] = %v, want %v", n, rule.Birth[n], wantB)
+				}
+				wantS := contains(c.wantSurvive, n)
+				if rule.Survive[n] != wantS {
+					t.Errorf("Survive[%d] = %v, want %v", n, rule.Survive[n], wantS)
+				}
+			}
+		})
+	}
+}
+
+// TestParseRulestringLegacyForm checks the legacy "survive/birth" notation
+// (e.g. "23/3", Conway's rule with no B/S letters), which ParseRulestring
+// falls back to when neither half contains a B or S.
+func TestParseRulestringLegacyForm(t *testing.T) {
+	rule, err := ParseRulestring("23/3")
+	if err != nil {
+		t.Fatalf("ParseRulestring(\"23/3\"): %v", err)
+	}
+	if rule != conwayRule {
+		t.Errorf("ParseRulestring(\"23/3\") = %+v, want %+v", rule, conwayRule)
+	}
+}
+
+func TestParseRulestringErrors(t *testing.T) {
+	cases := []string{"", "B3", "B9/S23", "Bx/S23", "3"}
+	for _, s := range cases {
+		t.Run(s, func(t *testing.T) {
+			if _, err := ParseRulestring(s); err == nil {
+				t.Errorf("ParseRulestring(%q) = nil error, want error", s)
+			}
+		})
+	}
+}
+
+// TestRuleStringRoundTrips checks String renders back into the canonical
+// B/S form ParseRulestring can re-parse to an identical rule.
+func TestRuleStringRoundTrips(t *testing.T) {
+	for _, nr := range namedRules {
+		t.Run(nr.name, func(t *testing.T) {
+			rule, err := ParseRulestring(nr.rule)
+			if err != nil {
+				t.Fatalf("ParseRulestring(%q): %v", nr.rule, err)
+			}
+			s := rule.String()
+			reparsed, err := ParseRulestring(s)
+			if err != nil {
+				t.Fatalf("ParseRulestring(%q) (round-trip): %v", s, err)
+			}
+			if reparsed != rule {
+				t.Errorf("round-trip through %q changed the rule: %+v != %+v", s, reparsed, rule)
+			}
+		})
+	}
+}
+
+func contains(ns []int, n int) bool {
+	for _, x := range ns {
+		if x == n {
+			return true
+		}
+	}
+	return false
+}